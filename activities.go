@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johntaormina/backend-runner/strava"
+)
+
+const stravaAPIBase = "https://www.strava.com/api/v3"
+
+// ListActivitiesOptions controls paging and time filtering for
+// ListActivities.
+type ListActivitiesOptions struct {
+	Before  time.Time
+	After   time.Time
+	Page    int
+	PerPage int
+}
+
+// ListSegmentEffortsOptions controls which segment's efforts are fetched and
+// how they're paged.
+type ListSegmentEffortsOptions struct {
+	SegmentID int64
+	Before    time.Time
+	After     time.Time
+	Page      int
+	PerPage   int
+}
+
+// get issues an authenticated GET request against the Strava API and decodes
+// the JSON response body into out. Authentication, retry-on-401, rate-limit
+// handling, and 5xx backoff are handled by do.
+func (c *StravaClient) get(ctx context.Context, path string, query url.Values, out any) error {
+	body, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, stravaAPIBase+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if query != nil {
+			req.URL.RawQuery = query.Encode()
+		}
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// ListActivities fetches a page of the authenticated athlete's activities.
+func (c *StravaClient) ListActivities(ctx context.Context, opts ListActivitiesOptions) ([]strava.SummaryActivity, error) {
+	q := url.Values{}
+	if !opts.Before.IsZero() {
+		q.Set("before", strconv.FormatInt(opts.Before.Unix(), 10))
+	}
+	if !opts.After.IsZero() {
+		q.Set("after", strconv.FormatInt(opts.After.Unix(), 10))
+	}
+	if opts.Page > 0 {
+		q.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	var activities []strava.SummaryActivity
+	if err := c.get(ctx, "/athlete/activities", q, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// ListActivitiesAll returns an iterator function that transparently pages
+// through every activity matching opts. It returns io.EOF once exhausted.
+func (c *StravaClient) ListActivitiesAll(ctx context.Context, opts ListActivitiesOptions) func() (*strava.SummaryActivity, error) {
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := opts.PerPage
+	if perPage == 0 {
+		perPage = 30
+	}
+
+	var buf []strava.SummaryActivity
+	var idx int
+
+	return func() (*strava.SummaryActivity, error) {
+		for idx >= len(buf) {
+			pageOpts := opts
+			pageOpts.Page = page
+			pageOpts.PerPage = perPage
+
+			activities, err := c.ListActivities(ctx, pageOpts)
+			if err != nil {
+				return nil, err
+			}
+			if len(activities) == 0 {
+				return nil, io.EOF
+			}
+
+			buf = activities
+			idx = 0
+			page++
+		}
+
+		activity := buf[idx]
+		idx++
+		return &activity, nil
+	}
+}
+
+// GetActivity fetches the full detail for a single activity, including laps
+// and segment efforts.
+func (c *StravaClient) GetActivity(ctx context.Context, id int64) (*strava.DetailedActivity, error) {
+	var activity strava.DetailedActivity
+	if err := c.get(ctx, fmt.Sprintf("/activities/%d", id), nil, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// ListSegmentEfforts fetches the authenticated athlete's efforts on a
+// segment.
+func (c *StravaClient) ListSegmentEfforts(ctx context.Context, opts ListSegmentEffortsOptions) ([]strava.SegmentEffort, error) {
+	q := url.Values{}
+	if !opts.After.IsZero() {
+		q.Set("start_date_local", opts.After.Format(time.RFC3339))
+	}
+	if !opts.Before.IsZero() {
+		q.Set("end_date_local", opts.Before.Format(time.RFC3339))
+	}
+	if opts.Page > 0 {
+		q.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	var efforts []strava.SegmentEffort
+	if err := c.get(ctx, fmt.Sprintf("/segments/%d/all_efforts", opts.SegmentID), q, &efforts); err != nil {
+		return nil, err
+	}
+	return efforts, nil
+}
+
+// GetAthleteStats fetches the aggregate activity totals for an athlete.
+func (c *StravaClient) GetAthleteStats(ctx context.Context, athleteID int64) (*strava.AthleteStats, error) {
+	var stats strava.AthleteStats
+	if err := c.get(ctx, fmt.Sprintf("/athletes/%d/stats", athleteID), nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetActivityStreams fetches the requested stream types (e.g. "time",
+// "latlng", "heartrate") for an activity.
+func (c *StravaClient) GetActivityStreams(ctx context.Context, activityID int64, types []string) ([]strava.Stream, error) {
+	q := url.Values{}
+	q.Set("keys", strings.Join(types, ","))
+	q.Set("key_by_type", "false")
+
+	var streams []strava.Stream
+	if err := c.get(ctx, fmt.Sprintf("/activities/%d/streams", activityID), q, &streams); err != nil {
+		return nil, err
+	}
+	return streams, nil
+}
+
+// CreateActivityRequest describes the fields accepted by Strava's manual
+// activity-creation endpoint, used when we don't have a raw file (GPX/TCX)
+// to upload.
+type CreateActivityRequest struct {
+	Name        string
+	SportType   strava.SportType
+	StartDate   time.Time
+	ElapsedTime time.Duration
+	Distance    float64 // meters
+}
+
+// CreateActivity creates a manual activity on behalf of the athlete.
+func (c *StravaClient) CreateActivity(ctx context.Context, req CreateActivityRequest) (*strava.SummaryActivity, error) {
+	form := url.Values{}
+	form.Set("name", req.Name)
+	form.Set("sport_type", string(req.SportType))
+	form.Set("start_date_local", req.StartDate.Format(time.RFC3339))
+	form.Set("elapsed_time", strconv.Itoa(int(req.ElapsedTime.Seconds())))
+	form.Set("distance", strconv.FormatFloat(req.Distance, 'f', -1, 64))
+
+	encoded := form.Encode()
+	body, err := c.do(ctx, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, stravaAPIBase+"/activities", strings.NewReader(encoded))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return httpReq, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var activity strava.SummaryActivity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
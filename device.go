@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mdp/qrterminal/v3"
+
+	"github.com/johntaormina/backend-runner/oauth"
+)
+
+// AuthorizeDevice runs the OAuth 2.0 Device Authorization Grant (RFC 8628)
+// for environments without a browser to redirect through, e.g. over SSH or
+// in a container. It prints the user code and verification URL, renders a
+// QR code for it, and blocks until the user approves, denies, or the code
+// expires.
+func (c *StravaClient) AuthorizeDevice(ctx context.Context) error {
+	device, err := c.OAuth.AuthorizeDevice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	fmt.Printf("To authorize this device, visit %s and enter code: %s\n", device.VerificationURI, device.UserCode)
+	qrterminal.GenerateHalfBlock(device.VerificationURI, qrterminal.L, os.Stdout)
+
+	if _, err := c.OAuth.PollDeviceToken(ctx, device); err != nil {
+		return fmt.Errorf("device authorization failed: %w", err)
+	}
+	return nil
+}
+
+// runLoginDeviceCommand backs the `runner login --device` subcommand: it runs
+// the Device Authorization Grant instead of the browser redirect
+// NewStravaClient uses, for headless environments (no browser to redirect
+// through) or for providers other than Strava set up via
+// STRAVA_DEVICE_AUTHORIZATION_URL (Strava itself has no device-authorization
+// endpoint).
+func runLoginDeviceCommand(ctx context.Context) error {
+	config := StravaConfig{
+		ClientID:               os.Getenv("STRAVA_CLIENT_ID"),
+		ClientSecret:           os.Getenv("STRAVA_CLIENT_SECRET"),
+		DeviceAuthorizationURL: os.Getenv("STRAVA_DEVICE_AUTHORIZATION_URL"),
+	}
+
+	oauthClient := newStravaOAuth(config, oauth.NewFileCache("strava_token.json"))
+	client := &StravaClient{
+		Config: config,
+		OAuth:  oauthClient,
+		HTTP:   oauthClient.AuthenticatedHTTPClient(),
+	}
+
+	return client.AuthorizeDevice(ctx)
+}
@@ -0,0 +1,184 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TokenCache stores tokens keyed by an opaque string (see Client's use of
+// SegmentName + AccessTokenKey/RefreshTokenKey). ttl in Set is how long the
+// cache considers the entry fresh; a zero ttl means it never expires on its
+// own (the token's own ExpiresAt still governs refresh).
+type TokenCache interface {
+	Get(key string) (Token, bool)
+	Set(key string, tok Token, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// MemoryCache is a TokenCache that only lives as long as the process. Handy
+// for tests and for providers that re-authorize on every run.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Token     Token
+	ExpiresAt time.Time
+}
+
+func (e cacheEntry) expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
+// NewMemoryCache builds an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+func (m *MemoryCache) Get(key string) (Token, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || entry.expired() {
+		return Token{}, false
+	}
+	return entry.Token, true
+}
+
+func (m *MemoryCache) Set(key string, tok Token, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := cacheEntry{Token: tok}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *MemoryCache) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// FileCache is a TokenCache backed by a single JSON file, storing a
+// namespaced map[string]cacheEntry (e.g. "strava:access_token") so multiple
+// providers/accounts can share one file. A file in the old flat
+// {access_token, refresh_token, expires_at} shape written by the
+// pre-cacheable Strava client is migrated on read; see migrateLegacyToken.
+type FileCache struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileCache builds a FileCache writing to path.
+func NewFileCache(path string) *FileCache {
+	return &FileCache{Path: path}
+}
+
+func (f *FileCache) load() (map[string]cacheEntry, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]cacheEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]cacheEntry{}
+	if err := json.Unmarshal(data, &entries); err == nil {
+		return entries, nil
+	}
+
+	if legacy, ok := migrateLegacyToken(data); ok {
+		return legacy, nil
+	}
+	return nil, fmt.Errorf("oauth: %s is not a recognized token cache format", f.Path)
+}
+
+// migrateLegacyToken recognizes the flat {access_token, refresh_token,
+// expires_at} shape strava_token.json was written in before tokens were
+// cacheable (always a single Strava account, with no per-segment
+// namespacing) and migrates it into the namespaced shape FileCache now
+// reads and writes, so an existing token file doesn't force a re-authorize.
+func migrateLegacyToken(data []byte) (map[string]cacheEntry, bool) {
+	var legacy struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresAt    int64  `json:"expires_at"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil || legacy.AccessToken == "" {
+		return nil, false
+	}
+
+	access := Token{AccessToken: legacy.AccessToken, RefreshToken: legacy.RefreshToken}
+	if legacy.ExpiresAt != 0 {
+		access.ExpiresAt = time.Unix(legacy.ExpiresAt, 0)
+	}
+	return map[string]cacheEntry{
+		"strava:access_token":  {Token: access},
+		"strava:refresh_token": {Token: Token{AccessToken: legacy.RefreshToken}},
+	}, true
+}
+
+func (f *FileCache) save(entries map[string]cacheEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, data, 0o600)
+}
+
+func (f *FileCache) Get(key string) (Token, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return Token{}, false
+	}
+	entry, ok := entries[key]
+	if !ok || entry.expired() {
+		return Token{}, false
+	}
+	return entry.Token, true
+}
+
+func (f *FileCache) Set(key string, tok Token, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	entry := cacheEntry{Token: tok}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+	entries[key] = entry
+	return f.save(entries)
+}
+
+func (f *FileCache) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	delete(entries, key)
+	return f.save(entries)
+}
@@ -0,0 +1,27 @@
+package oauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCacheMigratesLegacyFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strava_token.json")
+	legacy := `{"access_token":"atok","refresh_token":"rtok","expires_at":1999999999,"expires_in":21600,"token_type":"Bearer"}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("write legacy token file: %v", err)
+	}
+
+	cache := NewFileCache(path)
+	tok, ok := cache.Get("strava:access_token")
+	if !ok {
+		t.Fatal("expected a legacy token file to migrate and be found")
+	}
+	if tok.AccessToken != "atok" || tok.RefreshToken != "rtok" {
+		t.Fatalf("tok = %+v, want access=atok refresh=rtok", tok)
+	}
+	if tok.ExpiresAt.Unix() != 1999999999 {
+		t.Fatalf("ExpiresAt = %v, want unix 1999999999", tok.ExpiresAt)
+	}
+}
@@ -0,0 +1,135 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeviceAuthorization is the response from the device-authorization
+// endpoint, per RFC 8628 section 3.2.
+type DeviceAuthorization struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	ExpiresIn       int
+	Interval        int
+}
+
+// AuthorizeDevice starts the OAuth 2.0 Device Authorization Grant (RFC
+// 8628): it posts to OAuth.DeviceAuthorizationURL to obtain a device code
+// and the user code the caller should display.
+func (c *Client) AuthorizeDevice(ctx context.Context) (*DeviceAuthorization, error) {
+	if c.OAuth.DeviceAuthorizationURL == "" {
+		return nil, fmt.Errorf("oauth: device authorization not supported, no DeviceAuthorizationURL configured")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.OAuth.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.OAuth.DeviceAuthorizationURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("oauth: device authorization request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var payload struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("oauth: failed to parse device authorization response: %w", err)
+	}
+
+	return &DeviceAuthorization{
+		DeviceCode:      payload.DeviceCode,
+		UserCode:        payload.UserCode,
+		VerificationURI: payload.VerificationURI,
+		ExpiresIn:       payload.ExpiresIn,
+		Interval:        payload.Interval,
+	}, nil
+}
+
+// PollDeviceToken polls OAuth.TokenURL per RFC 8628 section 3.5 until the
+// user approves or denies the device, or device.ExpiresIn elapses. It
+// blocks, sleeping between polls, so callers should run it with a
+// cancellable ctx.
+func (c *Client) PollDeviceToken(ctx context.Context, device *DeviceAuthorization) (Token, error) {
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return Token{}, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if time.Now().After(deadline) {
+			return Token{}, fmt.Errorf("oauth: device code expired before authorization")
+		}
+
+		form := url.Values{}
+		form.Set("client_id", c.OAuth.ClientID)
+		form.Set("client_secret", c.OAuth.ClientSecret)
+		form.Set("device_code", device.DeviceCode)
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+
+		tok, err := c.requestToken(ctx, form)
+		if err == nil {
+			if err := c.Save(tok); err != nil {
+				return Token{}, err
+			}
+			return tok, nil
+		}
+
+		var tokErr *tokenEndpointError
+		if !errors.As(err, &tokErr) {
+			return Token{}, err
+		}
+
+		var body struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal([]byte(tokErr.Body), &body)
+
+		switch body.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			return Token{}, fmt.Errorf("oauth: device authorization denied by user")
+		case "expired_token":
+			return Token{}, fmt.Errorf("oauth: device code expired")
+		default:
+			return Token{}, err
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package oauth
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringCache is a TokenCache backed by the OS keyring (Keychain on macOS,
+// Secret Service on Linux, Credential Manager on Windows).
+type KeyringCache struct {
+	// Service is the keyring service name tokens are stored under.
+	Service string
+}
+
+// NewKeyringCache builds a KeyringCache under the given service name.
+func NewKeyringCache(service string) *KeyringCache {
+	return &KeyringCache{Service: service}
+}
+
+func (k *KeyringCache) Get(key string) (Token, bool) {
+	raw, err := keyring.Get(k.Service, key)
+	if err != nil {
+		return Token{}, false
+	}
+
+	var tok Token
+	if err := json.Unmarshal([]byte(raw), &tok); err != nil {
+		return Token{}, false
+	}
+	return tok, true
+}
+
+// Set stores tok in the keyring. ttl is ignored: OS keyrings don't support
+// expiring entries, so the token's own ExpiresAt is what governs refresh.
+func (k *KeyringCache) Set(key string, tok Token, ttl time.Duration) error {
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	return keyring.Set(k.Service, key, string(data))
+}
+
+func (k *KeyringCache) Delete(key string) error {
+	return keyring.Delete(k.Service, key)
+}
@@ -0,0 +1,347 @@
+// Package oauth implements a provider-agnostic OAuth2 authorization-code
+// client: token caching, lazy refresh, and a transparent http.RoundTripper,
+// so every fitness provider doesn't need to reimplement it.
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Token is an OAuth2 access/refresh token pair.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// expired reports whether the token is expired, or will expire within skew.
+func (t Token) expired(skew time.Duration) bool {
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(skew).After(t.ExpiresAt)
+}
+
+// OAuth describes one provider's OAuth2 endpoints and the cache keys its
+// tokens are stored under.
+type OAuth struct {
+	ClientID     string
+	ClientSecret string
+	AuthorizeURL string
+	TokenURL     string
+	RedirectURI  string
+
+	// DeviceAuthorizationURL is the RFC 8628 device-authorization endpoint.
+	// Leave empty for providers (like Strava) that don't support the device
+	// flow; AuthorizeDevice will refuse to run.
+	DeviceAuthorizationURL string
+
+	// AccessTokenKey and RefreshTokenKey namespace the cached Token so
+	// multiple OAuth-protected accounts can share one Cache.
+	AccessTokenKey  string
+	RefreshTokenKey string
+
+	// SegmentName groups AccessTokenKey/RefreshTokenKey under a provider
+	// namespace, e.g. "strava", so a Cache shared across providers doesn't
+	// collide on generic key names like "access_token".
+	SegmentName string
+
+	// RefreshSkew is how far ahead of ExpiresAt a lazy AccessToken call
+	// proactively refreshes. Defaults to 5 minutes.
+	RefreshSkew time.Duration
+
+	// TokenRetry controls requestToken's retry/backoff for 429s and 5xx
+	// responses from TokenURL. The zero value falls back to
+	// defaultTokenRetryConfig.
+	TokenRetry TokenRetryConfig
+}
+
+// InvalidRefreshTokenError indicates the stored refresh token was rejected
+// by the provider, meaning the user needs to go through authorization again.
+type InvalidRefreshTokenError struct {
+	Cause error
+}
+
+func (e *InvalidRefreshTokenError) Error() string {
+	return fmt.Sprintf("oauth: refresh token invalid, re-authorization required: %v", e.Cause)
+}
+
+func (e *InvalidRefreshTokenError) Unwrap() error {
+	return e.Cause
+}
+
+// Client lazily produces a valid access token for a single OAuth-protected
+// account, refreshing through Cache and OAuth.TokenURL as needed.
+type Client struct {
+	OAuth      OAuth
+	Cache      TokenCache
+	HTTPClient *http.Client
+
+	// pendingState and pendingPKCE are minted by BeginAuthorization and
+	// consumed by VerifyState/ExchangeCode. They're scoped to a single
+	// Client instance, so a fresh NewStravaClient-style invocation always
+	// gets its own unguessable state and code_verifier.
+	pendingState string
+	pendingPKCE  PKCE
+}
+
+// NewClient builds a Client. HTTPClient defaults to http.DefaultClient.
+func NewClient(o OAuth, cache TokenCache) *Client {
+	if o.RefreshSkew == 0 {
+		o.RefreshSkew = 5 * time.Minute
+	}
+	return &Client{
+		OAuth:      o,
+		Cache:      cache,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+func (c *Client) accessCacheKey() string {
+	return c.OAuth.SegmentName + ":" + c.OAuth.AccessTokenKey
+}
+
+func (c *Client) refreshCacheKey() string {
+	return c.OAuth.SegmentName + ":" + c.OAuth.RefreshTokenKey
+}
+
+// load reads the cached token, reassembling the refresh token from its own
+// cache entry if the access-token entry doesn't carry one.
+func (c *Client) load() (Token, bool) {
+	tok, ok := c.Cache.Get(c.accessCacheKey())
+	if !ok {
+		return Token{}, false
+	}
+	if tok.RefreshToken == "" {
+		if refreshTok, ok := c.Cache.Get(c.refreshCacheKey()); ok {
+			tok.RefreshToken = refreshTok.AccessToken
+		}
+	}
+	return tok, true
+}
+
+// Save persists a token, splitting the refresh token into its own cache
+// entry so callers (e.g. an OS keyring) can apply different retention to
+// access vs. refresh tokens.
+func (c *Client) Save(tok Token) error {
+	if err := c.Cache.Set(c.accessCacheKey(), tok, 0); err != nil {
+		return err
+	}
+	return c.Cache.Set(c.refreshCacheKey(), Token{AccessToken: tok.RefreshToken}, 0)
+}
+
+// AccessToken returns a valid access token, transparently refreshing it if
+// it's missing or within OAuth.RefreshSkew of expiring.
+func (c *Client) AccessToken(ctx context.Context) (string, error) {
+	tok, ok := c.load()
+	if !ok {
+		return "", fmt.Errorf("oauth: no cached token, authorization required")
+	}
+	if !tok.expired(c.OAuth.RefreshSkew) {
+		return tok.AccessToken, nil
+	}
+
+	refreshed, err := c.Refresh(ctx, tok.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+// ForceRefresh unconditionally exchanges the cached refresh token for a new
+// access token, bypassing the ExpiresAt/RefreshSkew check AccessToken
+// applies. Callers reach for this when they already know the cached access
+// token is bad despite looking unexpired, e.g. after the provider itself
+// rejects it with a 401 (revocation, clock skew, a scope change).
+func (c *Client) ForceRefresh(ctx context.Context) (Token, error) {
+	tok, ok := c.load()
+	if !ok {
+		return Token{}, fmt.Errorf("oauth: no cached token, authorization required")
+	}
+	return c.Refresh(ctx, tok.RefreshToken)
+}
+
+// AuthorizeOptions customizes the URL BeginAuthorization builds.
+type AuthorizeOptions struct {
+	Scope string
+}
+
+// BeginAuthorization mints a fresh CSRF state token and PKCE pair, and
+// returns the URL the user should visit to grant access. The state and
+// code_verifier are remembered on c and are consumed by VerifyState and
+// ExchangeCode respectively.
+func (c *Client) BeginAuthorization(opts AuthorizeOptions) (string, error) {
+	state, err := NewState()
+	if err != nil {
+		return "", fmt.Errorf("oauth: failed to generate state: %w", err)
+	}
+	pkce, err := NewPKCE()
+	if err != nil {
+		return "", fmt.Errorf("oauth: failed to generate PKCE pair: %w", err)
+	}
+	c.pendingState = state
+	c.pendingPKCE = pkce
+
+	q := url.Values{}
+	q.Set("client_id", c.OAuth.ClientID)
+	q.Set("redirect_uri", c.OAuth.RedirectURI)
+	q.Set("response_type", "code")
+	if opts.Scope != "" {
+		q.Set("scope", opts.Scope)
+	}
+	q.Set("state", state)
+	q.Set("code_challenge", pkce.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	return c.OAuth.AuthorizeURL + "?" + q.Encode(), nil
+}
+
+// VerifyState reports whether state matches the one minted by the most
+// recent BeginAuthorization call, guarding the callback against CSRF: a
+// request to the callback URL that didn't originate from our own
+// BeginAuthorization redirect won't carry the right value.
+func (c *Client) VerifyState(state string) bool {
+	if state == "" || c.pendingState == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(state), []byte(c.pendingState)) == 1
+}
+
+// ExchangeCode trades an OAuth2 authorization code for a token and caches
+// it. If BeginAuthorization was called on c, the matching PKCE
+// code_verifier is sent along so the provider can reject a code stolen from
+// a different authorization attempt.
+func (c *Client) ExchangeCode(ctx context.Context, code string) (Token, error) {
+	form := url.Values{}
+	form.Set("client_id", c.OAuth.ClientID)
+	form.Set("client_secret", c.OAuth.ClientSecret)
+	form.Set("redirect_uri", c.OAuth.RedirectURI)
+	form.Set("code", code)
+	form.Set("grant_type", "authorization_code")
+	if c.pendingPKCE.CodeVerifier != "" {
+		form.Set("code_verifier", c.pendingPKCE.CodeVerifier)
+	}
+
+	tok, err := c.requestToken(ctx, form)
+	if err != nil {
+		return Token{}, err
+	}
+	if err := c.Save(tok); err != nil {
+		return Token{}, err
+	}
+	return tok, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and caches it.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (Token, error) {
+	form := url.Values{}
+	form.Set("client_id", c.OAuth.ClientID)
+	form.Set("client_secret", c.OAuth.ClientSecret)
+	form.Set("refresh_token", refreshToken)
+	form.Set("grant_type", "refresh_token")
+
+	tok, err := c.requestToken(ctx, form)
+	if err != nil {
+		var apiErr *tokenEndpointError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return Token{}, &InvalidRefreshTokenError{Cause: err}
+		}
+		return Token{}, err
+	}
+	if err := c.Save(tok); err != nil {
+		return Token{}, err
+	}
+	return tok, nil
+}
+
+type tokenEndpointError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *tokenEndpointError) Error() string {
+	return fmt.Sprintf("oauth: token endpoint returned %d: %s", e.StatusCode, e.Body)
+}
+
+// requestToken posts form to OAuth.TokenURL and decodes the resulting token,
+// retrying 429 and 5xx responses with backoff (honoring Retry-After on a
+// 429) the same way StravaClient.do retries the main API — a rate-limited or
+// momentarily-down token endpoint shouldn't fail a refresh outright.
+func (c *Client) requestToken(ctx context.Context, form url.Values) (Token, error) {
+	retry := c.OAuth.TokenRetry
+	if retry.MaxRetries == 0 && retry.BaseDelay == 0 {
+		retry = defaultTokenRetryConfig
+	}
+
+	var body []byte
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.OAuth.TokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return Token{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return Token{}, err
+		}
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return Token{}, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+
+		retriable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retriable || attempt >= retry.MaxRetries {
+			return Token{}, &tokenEndpointError{StatusCode: resp.StatusCode, Body: string(body)}
+		}
+
+		wait, ok := retryAfter(resp.Header)
+		if !ok {
+			wait = tokenRetryBackoff(retry, attempt)
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return Token{}, err
+		}
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresAt    int64  `json:"expires_at"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Token{}, fmt.Errorf("oauth: failed to parse token response: %w", err)
+	}
+
+	var expiresAt time.Time
+	switch {
+	case payload.ExpiresAt != 0:
+		// Strava's own shape: an absolute unix timestamp.
+		expiresAt = time.Unix(payload.ExpiresAt, 0)
+	case payload.ExpiresIn != 0:
+		// RFC 6749's shape (Google Fit, Fitbit, generic OIDC): seconds
+		// remaining, relative to this response.
+		expiresAt = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+
+	return Token{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyStateRejectsCSRF(t *testing.T) {
+	client := NewClient(OAuth{SegmentName: "test", AccessTokenKey: "a", RefreshTokenKey: "r"}, NewMemoryCache())
+
+	if _, err := client.BeginAuthorization(AuthorizeOptions{}); err != nil {
+		t.Fatalf("BeginAuthorization: %v", err)
+	}
+
+	if client.VerifyState("") {
+		t.Fatal("expected empty state to be rejected")
+	}
+	if client.VerifyState("attacker-supplied-state") {
+		t.Fatal("expected a state an attacker could supply by navigating straight to the callback URL to be rejected")
+	}
+	if !client.VerifyState(client.pendingState) {
+		t.Fatal("expected the state minted by BeginAuthorization to verify")
+	}
+}
+
+func TestExchangeCodeSendsMatchingPKCEVerifier(t *testing.T) {
+	var gotVerifier, wantChallenge string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		gotVerifier = r.FormValue("code_verifier")
+
+		// Emulate an OAuth2 server validating PKCE: the verifier's SHA256
+		// must match the code_challenge presented at authorize time.
+		sum := sha256.Sum256([]byte(gotVerifier))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != wantChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "token123",
+			"refresh_token": "refresh123",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(OAuth{
+		TokenURL:        server.URL,
+		SegmentName:     "test",
+		AccessTokenKey:  "a",
+		RefreshTokenKey: "r",
+	}, NewMemoryCache())
+
+	if _, err := client.BeginAuthorization(AuthorizeOptions{}); err != nil {
+		t.Fatalf("BeginAuthorization: %v", err)
+	}
+	wantChallenge = client.pendingPKCE.CodeChallenge
+
+	if _, err := client.ExchangeCode(context.Background(), "auth-code"); err != nil {
+		t.Fatalf("ExchangeCode with matching verifier: %v", err)
+	}
+	if gotVerifier != client.pendingPKCE.CodeVerifier {
+		t.Fatalf("server saw verifier %q, want %q", gotVerifier, client.pendingPKCE.CodeVerifier)
+	}
+}
+
+func TestExchangeCodeRejectsMismatchedPKCEVerifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A real provider rejects any code_verifier that doesn't hash to
+		// the code_challenge it was given at authorize time.
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+	}))
+	defer server.Close()
+
+	client := NewClient(OAuth{
+		TokenURL:        server.URL,
+		SegmentName:     "test",
+		AccessTokenKey:  "a",
+		RefreshTokenKey: "r",
+	}, NewMemoryCache())
+
+	if _, err := client.BeginAuthorization(AuthorizeOptions{}); err != nil {
+		t.Fatalf("BeginAuthorization: %v", err)
+	}
+	// Simulate an attacker who intercepted the authorization code but not
+	// the original code_verifier.
+	client.pendingPKCE.CodeVerifier = "attacker-supplied-verifier"
+
+	if _, err := client.ExchangeCode(context.Background(), "auth-code"); err == nil {
+		t.Fatal("expected a PKCE-mismatched exchange to fail")
+	}
+}
+
+func TestRequestTokenParsesExpiresIn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "token123",
+			"refresh_token": "refresh123",
+			"expires_in":    3600,
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(OAuth{
+		TokenURL:        server.URL,
+		SegmentName:     "test",
+		AccessTokenKey:  "a",
+		RefreshTokenKey: "r",
+	}, NewMemoryCache())
+
+	before := time.Now()
+	tok, err := client.ExchangeCode(context.Background(), "auth-code")
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if tok.ExpiresAt.Before(before.Add(3599 * time.Second)) {
+		t.Fatalf("ExpiresAt = %v, want ~1 hour from now", tok.ExpiresAt)
+	}
+	if tok.expired(0) {
+		t.Fatal("a token that expires in an hour shouldn't report as expired")
+	}
+}
+
+func TestRequestTokenRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "token123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(OAuth{
+		TokenURL:        server.URL,
+		SegmentName:     "test",
+		AccessTokenKey:  "a",
+		RefreshTokenKey: "r",
+		TokenRetry:      TokenRetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond},
+	}, NewMemoryCache())
+
+	if _, err := client.ExchangeCode(context.Background(), "auth-code"); err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRequestTokenGivesUpOn5xxAfterMaxRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(OAuth{
+		TokenURL:        server.URL,
+		SegmentName:     "test",
+		AccessTokenKey:  "a",
+		RefreshTokenKey: "r",
+		TokenRetry:      TokenRetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+	}, NewMemoryCache())
+
+	if _, err := client.ExchangeCode(context.Background(), "auth-code"); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if calls != 3 { // initial attempt + 2 retries
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
@@ -0,0 +1,62 @@
+package oauth
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TokenRetryConfig controls requestToken's retry/backoff behavior for 429
+// and 5xx responses from OAuth.TokenURL.
+type TokenRetryConfig struct {
+	// MaxRetries is how many additional attempts are made after the first.
+	MaxRetries int
+
+	// BaseDelay is the backoff unit for 5xx retries: attempt N waits
+	// BaseDelay*2^N plus jitter in [0, BaseDelay). A 429 with a Retry-After
+	// header waits that long instead.
+	BaseDelay time.Duration
+}
+
+// defaultTokenRetryConfig is used whenever an OAuth doesn't set TokenRetry.
+var defaultTokenRetryConfig = TokenRetryConfig{MaxRetries: 3, BaseDelay: 500 * time.Millisecond}
+
+// retryAfter reports the delay requested by a Retry-After header (seconds or
+// an HTTP-date), and whether one was present.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// tokenRetryBackoff computes the exponential-backoff-plus-jitter wait for a
+// 5xx retry.
+func tokenRetryBackoff(retry TokenRetryConfig, attempt int) time.Duration {
+	backoff := retry.BaseDelay << attempt
+	if retry.BaseDelay > 0 {
+		backoff += time.Duration(rand.Int63n(int64(retry.BaseDelay)))
+	}
+	return backoff
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
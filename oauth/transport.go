@@ -0,0 +1,39 @@
+package oauth
+
+import "net/http"
+
+// Transport wraps an http.RoundTripper, attaching "Authorization: Bearer
+// ..." to every request and refreshing the token first if it's stale.
+type Transport struct {
+	// Base is the underlying RoundTripper. Defaults to
+	// http.DefaultTransport.
+	Base   http.RoundTripper
+	Client *Client
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	accessToken, err := t.Client.AccessToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// http.RoundTripper implementations must not mutate the request they're
+	// given; clone before setting the header.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	return t.base().RoundTrip(req)
+}
+
+// AuthenticatedHTTPClient returns an *http.Client that transparently
+// authenticates and refreshes requests made through it.
+func (c *Client) AuthenticatedHTTPClient() *http.Client {
+	return &http.Client{Transport: &Transport{Client: c}}
+}
@@ -0,0 +1,55 @@
+// Package provider defines a provider-agnostic abstraction over fitness
+// services (Strava, Runkeeper, ...) so the sync engine and CLI don't need to
+// know which backend they're talking to.
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// Token is a provider-agnostic OAuth2 token.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// Activity is a provider-agnostic summary of a single workout, suitable for
+// diffing and re-uploading across providers.
+type Activity struct {
+	ID        string
+	Name      string
+	SportType string
+	StartTime time.Time
+	Duration  time.Duration
+	Distance  float64 // meters
+
+	// Raw holds the provider's native representation, for callers that need
+	// more than the common fields above.
+	Raw any
+}
+
+// FitnessProvider is implemented by every supported fitness backend.
+type FitnessProvider interface {
+	// Authorize returns the URL the user should visit to grant access.
+	Authorize(ctx context.Context) (string, error)
+
+	// ExchangeCode trades an OAuth2 authorization code for a token.
+	ExchangeCode(ctx context.Context, code string) (Token, error)
+
+	// RefreshToken exchanges a refresh token for a new access token.
+	RefreshToken(ctx context.Context, refreshToken string) (Token, error)
+
+	// ListActivities returns activities created since the given time.
+	ListActivities(ctx context.Context, since time.Time) ([]Activity, error)
+
+	// UploadActivity creates activity on the provider from a
+	// provider-agnostic payload.
+	UploadActivity(ctx context.Context, activity Activity) error
+}
+
+// Factory constructs a FitnessProvider authenticated with the given access
+// token. It's the shape the sync package uses to turn a persisted
+// SyncTask's tokens into live provider clients.
+type Factory func(accessToken string) (FitnessProvider, error)
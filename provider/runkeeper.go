@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	runkeeperAuthorizeURL = "https://runkeeper.com/apps/authorize"
+	runkeeperTokenURL     = "https://runkeeper.com/apps/token"
+	runkeeperAPIBase      = "https://api.runkeeper.com"
+)
+
+// RunkeeperConfig holds the OAuth2 credentials for the Runkeeper Health
+// Graph API.
+type RunkeeperConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+}
+
+// RunkeeperProvider implements FitnessProvider against the Runkeeper Health
+// Graph API.
+type RunkeeperProvider struct {
+	Config      RunkeeperConfig
+	AccessToken string
+
+	httpClient *http.Client
+}
+
+// NewRunkeeperProvider builds a RunkeeperProvider. AccessToken may be empty
+// for a client that's only used to run the authorization flow.
+func NewRunkeeperProvider(config RunkeeperConfig, accessToken string) *RunkeeperProvider {
+	return &RunkeeperProvider{
+		Config:      config,
+		AccessToken: accessToken,
+		httpClient:  &http.Client{},
+	}
+}
+
+func (p *RunkeeperProvider) Authorize(ctx context.Context) (string, error) {
+	return fmt.Sprintf(
+		"%s?client_id=%s&redirect_uri=%s&response_type=code",
+		runkeeperAuthorizeURL,
+		p.Config.ClientID,
+		url.QueryEscape(p.Config.RedirectURI),
+	), nil
+}
+
+func (p *RunkeeperProvider) ExchangeCode(ctx context.Context, code string) (Token, error) {
+	form := url.Values{}
+	form.Set("client_id", p.Config.ClientID)
+	form.Set("client_secret", p.Config.ClientSecret)
+	form.Set("redirect_uri", p.Config.RedirectURI)
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+
+	return p.requestToken(ctx, form)
+}
+
+func (p *RunkeeperProvider) RefreshToken(ctx context.Context, refreshToken string) (Token, error) {
+	// Runkeeper access tokens don't expire under the Health Graph API, so
+	// there's nothing to refresh; callers holding an old token can keep
+	// using it or re-run the authorization flow.
+	return Token{}, fmt.Errorf("runkeeper: access tokens do not expire, refresh is not supported")
+}
+
+func (p *RunkeeperProvider) requestToken(ctx context.Context, form url.Values) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, runkeeperTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("runkeeper: token request failed: %s", body)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Token{}, fmt.Errorf("runkeeper: failed to parse token response: %w", err)
+	}
+
+	return Token{AccessToken: payload.AccessToken}, nil
+}
+
+// runkeeperActivity mirrors the fields Runkeeper's fitnessActivities feed
+// returns that we care about. StartTime is a naive wall-clock string in the
+// activity's local zone; UTCOffset (seconds, positive east of UTC) is how
+// far ahead of UTC that zone is.
+type runkeeperActivity struct {
+	URI           string  `json:"uri"`
+	Type          string  `json:"type"`
+	StartTime     string  `json:"start_time"`
+	UTCOffset     float64 `json:"utc_offset"`
+	Duration      float64 `json:"duration"`       // seconds
+	TotalDistance float64 `json:"total_distance"` // meters
+}
+
+func (p *RunkeeperProvider) ListActivities(ctx context.Context, since time.Time) ([]Activity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, runkeeperAPIBase+"/fitnessActivities", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("noEarlierThan", since.Format("2006-01-02"))
+	req.URL.RawQuery = q.Encode()
+	p.authenticate(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("runkeeper: list activities failed: %s", body)
+	}
+
+	var feed struct {
+		Items []runkeeperActivity `json:"items"`
+	}
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("runkeeper: failed to parse activities feed: %w", err)
+	}
+
+	activities := make([]Activity, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		startTime, err := time.Parse("Mon, 2 Jan 2006 15:04:05", item.StartTime)
+		if err != nil {
+			continue
+		}
+		// startTime was parsed as a naive wall clock with no zone, so Go
+		// attached UTC to it; shift by UTCOffset to land on the actual UTC
+		// instant.
+		startTime = startTime.Add(-time.Duration(item.UTCOffset) * time.Second)
+		if startTime.Before(since) {
+			continue
+		}
+		activities = append(activities, Activity{
+			ID:        item.URI,
+			Name:      item.Type,
+			SportType: item.Type,
+			StartTime: startTime,
+			Duration:  time.Duration(item.Duration * float64(time.Second)),
+			Distance:  item.TotalDistance,
+			Raw:       item,
+		})
+	}
+	return activities, nil
+}
+
+func (p *RunkeeperProvider) UploadActivity(ctx context.Context, activity Activity) error {
+	// Write the wall-clock string and utc_offset in UTC so the instant
+	// round-trips exactly regardless of activity.StartTime's original zone.
+	utcStart := activity.StartTime.UTC()
+	payload := map[string]any{
+		"type":           activity.SportType,
+		"start_time":     utcStart.Format("Mon, 2 Jan 2006 15:04:05"),
+		"utc_offset":     0,
+		"duration":       activity.Duration.Seconds(),
+		"total_distance": activity.Distance,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, runkeeperAPIBase+"/fitnessActivities", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.com.runkeeper.NewFitnessActivity+json")
+	p.authenticate(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("runkeeper: upload activity failed: %s", respBody)
+	}
+	return nil
+}
+
+func (p *RunkeeperProvider) authenticate(req *http.Request) {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.AccessToken))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/johntaormina/backend-runner/oauth"
+	"github.com/johntaormina/backend-runner/provider"
+	"github.com/johntaormina/backend-runner/strava"
+)
+
+// StravaFitnessProvider adapts StravaClient's richer, Strava-specific API to
+// the provider-agnostic provider.FitnessProvider interface so the sync
+// engine can treat Strava like any other backend.
+type StravaFitnessProvider struct {
+	Client *StravaClient
+}
+
+// NewStravaFitnessProvider wraps an existing StravaClient.
+func NewStravaFitnessProvider(client *StravaClient) *StravaFitnessProvider {
+	return &StravaFitnessProvider{Client: client}
+}
+
+func (p *StravaFitnessProvider) Authorize(ctx context.Context) (string, error) {
+	return p.Client.OAuth.BeginAuthorization(oauth.AuthorizeOptions{Scope: "read,activity:read_all,activity:write"})
+}
+
+func (p *StravaFitnessProvider) ExchangeCode(ctx context.Context, code string) (provider.Token, error) {
+	token, err := p.Client.OAuth.ExchangeCode(ctx, code)
+	if err != nil {
+		return provider.Token{}, err
+	}
+	return oauthTokenToProviderToken(token), nil
+}
+
+func (p *StravaFitnessProvider) RefreshToken(ctx context.Context, refreshToken string) (provider.Token, error) {
+	token, err := p.Client.OAuth.Refresh(ctx, refreshToken)
+	if err != nil {
+		return provider.Token{}, err
+	}
+	return oauthTokenToProviderToken(token), nil
+}
+
+func (p *StravaFitnessProvider) ListActivities(ctx context.Context, since time.Time) ([]provider.Activity, error) {
+	activities, err := p.Client.ListActivities(ctx, ListActivitiesOptions{After: since})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]provider.Activity, len(activities))
+	for i, a := range activities {
+		out[i] = summaryActivityToProviderActivity(a)
+	}
+	return out, nil
+}
+
+func (p *StravaFitnessProvider) UploadActivity(ctx context.Context, activity provider.Activity) error {
+	_, err := p.Client.CreateActivity(ctx, CreateActivityRequest{
+		Name:        activity.Name,
+		SportType:   strava.SportType(activity.SportType),
+		StartDate:   activity.StartTime,
+		ElapsedTime: activity.Duration,
+		Distance:    activity.Distance,
+	})
+	return err
+}
+
+func oauthTokenToProviderToken(token oauth.Token) provider.Token {
+	return provider.Token{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.ExpiresAt,
+	}
+}
+
+func summaryActivityToProviderActivity(a strava.SummaryActivity) provider.Activity {
+	return provider.Activity{
+		ID:        strconv.FormatInt(a.ID, 10),
+		Name:      a.Name,
+		SportType: string(a.SportType),
+		StartTime: a.StartDate,
+		Duration:  a.ElapsedTime.Duration(),
+		Distance:  a.Distance,
+		Raw:       a,
+	}
+}
@@ -0,0 +1,419 @@
+// Package strava provides typed Go representations of the Strava V3 API
+// resources, mirroring the field set exposed by community wrappers such as
+// go.strava. Every resource embeds a RawJSON escape hatch so callers can
+// reach fields this package hasn't caught up to yet without resorting to
+// map[string]any.
+package strava
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SportType is the activity's sport, e.g. "Run" or "Ride".
+type SportType string
+
+const (
+	SportTypeRun          SportType = "Run"
+	SportTypeTrailRun     SportType = "TrailRun"
+	SportTypeRide         SportType = "Ride"
+	SportTypeMountainBike SportType = "MountainBikeRide"
+	SportTypeGravelRide   SportType = "GravelRide"
+	SportTypeSwim         SportType = "Swim"
+	SportTypeHike         SportType = "Hike"
+	SportTypeWalk         SportType = "Walk"
+	SportTypeWorkout      SportType = "Workout"
+	SportTypeYoga         SportType = "Yoga"
+)
+
+// WorkoutType further qualifies a run or ride activity.
+type WorkoutType int
+
+const (
+	WorkoutTypeDefault WorkoutType = 0
+	WorkoutTypeRace    WorkoutType = 1
+	WorkoutTypeLongRun WorkoutType = 2
+	WorkoutTypeWorkout WorkoutType = 3
+)
+
+// Duration wraps time.Duration so Strava's integer-seconds fields
+// (elapsed_time, moving_time, ...) decode into a proper duration instead of
+// a raw number of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalJSON interprets the JSON number as a count of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return err
+	}
+	*d = Duration(secs * float64(time.Second))
+	return nil
+}
+
+// MarshalJSON writes the duration back out as a count of seconds.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// Duration returns the value as a standard time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// Athlete is a Strava athlete summary, as embedded in activities and
+// returned by the athlete endpoints.
+type Athlete struct {
+	ID            int64     `json:"id"`
+	Username      string    `json:"username"`
+	FirstName     string    `json:"firstname"`
+	LastName      string    `json:"lastname"`
+	City          string    `json:"city"`
+	State         string    `json:"state"`
+	Country       string    `json:"country"`
+	Sex           string    `json:"sex"`
+	Premium       bool      `json:"premium"`
+	Summit        bool      `json:"summit"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	ProfileMedium string    `json:"profile_medium"`
+	Profile       string    `json:"profile"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (a *Athlete) UnmarshalJSON(data []byte) error {
+	type alias Athlete
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*a = Athlete(v)
+	a.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// AthleteStats holds the aggregate totals returned by GetAthleteStats.
+type AthleteStats struct {
+	BiggestRideDistance   float64 `json:"biggest_ride_distance"`
+	BiggestClimbElevation float64 `json:"biggest_climb_elevation_gain"`
+	RecentRideTotals      Totals  `json:"recent_ride_totals"`
+	RecentRunTotals       Totals  `json:"recent_run_totals"`
+	RecentSwimTotals      Totals  `json:"recent_swim_totals"`
+	YtdRideTotals         Totals  `json:"ytd_ride_totals"`
+	YtdRunTotals          Totals  `json:"ytd_run_totals"`
+	YtdSwimTotals         Totals  `json:"ytd_swim_totals"`
+	AllRideTotals         Totals  `json:"all_ride_totals"`
+	AllRunTotals          Totals  `json:"all_run_totals"`
+	AllSwimTotals         Totals  `json:"all_swim_totals"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (s *AthleteStats) UnmarshalJSON(data []byte) error {
+	type alias AthleteStats
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = AthleteStats(v)
+	s.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Totals is a count/distance/time rollup, as used in AthleteStats.
+type Totals struct {
+	Count         int      `json:"count"`
+	Distance      float64  `json:"distance"`
+	MovingTime    Duration `json:"moving_time"`
+	ElapsedTime   Duration `json:"elapsed_time"`
+	ElevationGain float64  `json:"elevation_gain"`
+}
+
+// Gear is a piece of equipment (bike or shoes) associated with an activity.
+type Gear struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Distance      float64 `json:"distance"`
+	Primary       bool    `json:"primary"`
+	ResourceState int     `json:"resource_state"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (g *Gear) UnmarshalJSON(data []byte) error {
+	type alias Gear
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*g = Gear(v)
+	g.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// SummaryActivity is the representation returned by the activities list
+// endpoints.
+type SummaryActivity struct {
+	ID                 int64       `json:"id"`
+	ExternalID         string      `json:"external_id"`
+	Athlete            Athlete     `json:"athlete"`
+	Name               string      `json:"name"`
+	Distance           float64     `json:"distance"`
+	MovingTime         Duration    `json:"moving_time"`
+	ElapsedTime        Duration    `json:"elapsed_time"`
+	TotalElevationGain float64     `json:"total_elevation_gain"`
+	SportType          SportType   `json:"sport_type"`
+	WorkoutType        WorkoutType `json:"workout_type"`
+	StartDate          time.Time   `json:"start_date"`
+	StartDateLocal     time.Time   `json:"start_date_local"`
+	Timezone           string      `json:"timezone"`
+	AverageSpeed       float64     `json:"average_speed"`
+	MaxSpeed           float64     `json:"max_speed"`
+	AverageHeartrate   float64     `json:"average_heartrate"`
+	MaxHeartrate       float64     `json:"max_heartrate"`
+	ElevHigh           float64     `json:"elev_high"`
+	ElevLow            float64     `json:"elev_low"`
+	Trainer            bool        `json:"trainer"`
+	Commute            bool        `json:"commute"`
+	Manual             bool        `json:"manual"`
+	Private            bool        `json:"private"`
+	Gear               *Gear       `json:"gear,omitempty"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (s *SummaryActivity) UnmarshalJSON(data []byte) error {
+	type alias SummaryActivity
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = SummaryActivity(v)
+	s.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// DetailedActivity is the representation returned by GetActivity, including
+// laps, segment efforts and the full description.
+type DetailedActivity struct {
+	SummaryActivity
+
+	Description    string          `json:"description"`
+	Calories       float64         `json:"calories"`
+	Laps           []Lap           `json:"laps"`
+	SegmentEfforts []SegmentEffort `json:"segment_efforts"`
+	Gear           *Gear           `json:"gear,omitempty"`
+}
+
+// UnmarshalJSON decodes into a field-by-field alias rather than alias'ing
+// DetailedActivity directly: since SummaryActivity is embedded anonymously,
+// `type alias DetailedActivity` would promote *SummaryActivity's own
+// UnmarshalJSON onto alias, so json.Unmarshal would silently recurse into
+// that method instead of decoding alias's fields, leaving Description,
+// Calories, Laps, and SegmentEfforts zero-valued. aliasSummary has none of
+// SummaryActivity's methods, so embedding it breaks that recursion while
+// still flattening its JSON fields into the outer object.
+func (d *DetailedActivity) UnmarshalJSON(data []byte) error {
+	type aliasSummary SummaryActivity
+	type alias struct {
+		aliasSummary
+
+		Description    string          `json:"description"`
+		Calories       float64         `json:"calories"`
+		Laps           []Lap           `json:"laps"`
+		SegmentEfforts []SegmentEffort `json:"segment_efforts"`
+		Gear           *Gear           `json:"gear,omitempty"`
+	}
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*d = DetailedActivity{
+		SummaryActivity: SummaryActivity(v.aliasSummary),
+		Description:     v.Description,
+		Calories:        v.Calories,
+		Laps:            v.Laps,
+		SegmentEfforts:  v.SegmentEfforts,
+		Gear:            v.Gear,
+	}
+	d.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Lap is one interval of an activity split by the athlete or device.
+type Lap struct {
+	ID                 int64     `json:"id"`
+	ActivityID         int64     `json:"activity_id"`
+	Name               string    `json:"name"`
+	LapIndex           int       `json:"lap_index"`
+	Distance           float64   `json:"distance"`
+	MovingTime         Duration  `json:"moving_time"`
+	ElapsedTime        Duration  `json:"elapsed_time"`
+	StartDate          time.Time `json:"start_date"`
+	StartDateLocal     time.Time `json:"start_date_local"`
+	AverageSpeed       float64   `json:"average_speed"`
+	MaxSpeed           float64   `json:"max_speed"`
+	AverageHeartrate   float64   `json:"average_heartrate"`
+	TotalElevationGain float64   `json:"total_elevation_gain"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (l *Lap) UnmarshalJSON(data []byte) error {
+	type alias Lap
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = Lap(v)
+	l.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Segment is a Strava segment, a stretch of road or trail other athletes'
+// efforts are compared on.
+type Segment struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	ActivityType  string  `json:"activity_type"`
+	Distance      float64 `json:"distance"`
+	AverageGrade  float64 `json:"average_grade"`
+	MaximumGrade  float64 `json:"maximum_grade"`
+	ElevationHigh float64 `json:"elevation_high"`
+	ElevationLow  float64 `json:"elevation_low"`
+	ClimbCategory int     `json:"climb_category"`
+	City          string  `json:"city"`
+	State         string  `json:"state"`
+	Country       string  `json:"country"`
+	Private       bool    `json:"private"`
+	Starred       bool    `json:"starred"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	type alias Segment
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Segment(v)
+	s.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// SegmentEffort is one athlete's attempt at a Segment.
+type SegmentEffort struct {
+	ID               int64     `json:"id"`
+	ActivityID       int64     `json:"activity_id"`
+	Athlete          Athlete   `json:"athlete"`
+	Segment          Segment   `json:"segment"`
+	Name             string    `json:"name"`
+	ElapsedTime      Duration  `json:"elapsed_time"`
+	MovingTime       Duration  `json:"moving_time"`
+	StartDate        time.Time `json:"start_date"`
+	StartDateLocal   time.Time `json:"start_date_local"`
+	Distance         float64   `json:"distance"`
+	AverageHeartrate float64   `json:"average_heartrate"`
+	MaxHeartrate     float64   `json:"max_heartrate"`
+	KomRank          int       `json:"kom_rank"`
+	PrRank           int       `json:"pr_rank"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (e *SegmentEffort) UnmarshalJSON(data []byte) error {
+	type alias SegmentEffort
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = SegmentEffort(v)
+	e.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Stream is one channel (time, latlng, altitude, heartrate, ...) of an
+// activity's recorded data. Data's shape depends on Type: most streams
+// (time, altitude, heartrate, ...) are a []float64, but "latlng" is a
+// [][2]float64 of [lat, lng] pairs and "moving" is a []bool, so it's kept
+// as raw JSON and decoded on demand via Floats/LatLngs/Bools instead of
+// forcing one shape on every stream.
+type Stream struct {
+	Type         string          `json:"type"`
+	Data         json.RawMessage `json:"data"`
+	SeriesType   string          `json:"series_type"`
+	OriginalSize int             `json:"original_size"`
+	Resolution   string          `json:"resolution"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (s *Stream) UnmarshalJSON(data []byte) error {
+	type alias Stream
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Stream(v)
+	s.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Floats decodes Data as a []float64, the shape of every stream type except
+// "latlng" and "moving".
+func (s *Stream) Floats() ([]float64, error) {
+	var v []float64
+	if err := json.Unmarshal(s.Data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// LatLngs decodes Data as a [][2]float64 of [lat, lng] pairs, the shape of
+// the "latlng" stream type.
+func (s *Stream) LatLngs() ([][2]float64, error) {
+	var v [][2]float64
+	if err := json.Unmarshal(s.Data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Bools decodes Data as a []bool, the shape of the "moving" stream type.
+func (s *Stream) Bools() ([]bool, error) {
+	var v []bool
+	if err := json.Unmarshal(s.Data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Leaderboard holds the ranked entries for a segment.
+type Leaderboard struct {
+	EffortCount int                `json:"effort_count"`
+	EntryCount  int                `json:"entry_count"`
+	Entries     []LeaderboardEntry `json:"entries"`
+
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (l *Leaderboard) UnmarshalJSON(data []byte) error {
+	type alias Leaderboard
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = Leaderboard(v)
+	l.RawJSON = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// LeaderboardEntry is a single athlete's row on a segment Leaderboard.
+type LeaderboardEntry struct {
+	AthleteName string   `json:"athlete_name"`
+	ElapsedTime Duration `json:"elapsed_time"`
+	MovingTime  Duration `json:"moving_time"`
+	Rank        int      `json:"rank"`
+}
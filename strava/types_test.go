@@ -0,0 +1,104 @@
+package strava
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDetailedActivityUnmarshalJSONPopulatesOwnFields(t *testing.T) {
+	payload := `{
+		"id": 123,
+		"name": "Morning Run",
+		"distance": 5000,
+		"description": "Felt great out there.",
+		"calories": 412.5,
+		"laps": [{"id": 1, "name": "Lap 1"}],
+		"segment_efforts": [{"id": 2, "name": "Uphill Sprint"}],
+		"gear": {"id": "g1", "name": "Old Shoes"}
+	}`
+
+	var got DetailedActivity
+	if err := json.Unmarshal([]byte(payload), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.ID != 123 || got.Name != "Morning Run" {
+		t.Fatalf("promoted SummaryActivity fields not populated: %+v", got)
+	}
+	if got.Description != "Felt great out there." {
+		t.Errorf("Description = %q, want %q", got.Description, "Felt great out there.")
+	}
+	if got.Calories != 412.5 {
+		t.Errorf("Calories = %v, want 412.5", got.Calories)
+	}
+	if len(got.Laps) != 1 || got.Laps[0].Name != "Lap 1" {
+		t.Errorf("Laps = %+v, want one lap named Lap 1", got.Laps)
+	}
+	if len(got.SegmentEfforts) != 1 || got.SegmentEfforts[0].Name != "Uphill Sprint" {
+		t.Errorf("SegmentEfforts = %+v, want one effort named Uphill Sprint", got.SegmentEfforts)
+	}
+	if got.Gear == nil || got.Gear.Name != "Old Shoes" {
+		t.Errorf("Gear = %+v, want a gear named Old Shoes", got.Gear)
+	}
+	if string(got.RawJSON) != payload {
+		t.Errorf("RawJSON not captured verbatim")
+	}
+}
+
+func TestStreamFloats(t *testing.T) {
+	var s Stream
+	if err := json.Unmarshal([]byte(`{"type":"heartrate","data":[120,121,125]}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	got, err := s.Floats()
+	if err != nil {
+		t.Fatalf("Floats: %v", err)
+	}
+	want := []float64{120, 121, 125}
+	if len(got) != len(want) {
+		t.Fatalf("Floats = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Floats = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestStreamLatLngs(t *testing.T) {
+	var s Stream
+	if err := json.Unmarshal([]byte(`{"type":"latlng","data":[[37.1,-122.1],[37.2,-122.2]]}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	got, err := s.LatLngs()
+	if err != nil {
+		t.Fatalf("LatLngs: %v", err)
+	}
+	want := [][2]float64{{37.1, -122.1}, {37.2, -122.2}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("LatLngs = %v, want %v", got, want)
+	}
+}
+
+func TestStreamBools(t *testing.T) {
+	var s Stream
+	if err := json.Unmarshal([]byte(`{"type":"moving","data":[true,false,true]}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	got, err := s.Bools()
+	if err != nil {
+		t.Fatalf("Bools: %v", err)
+	}
+	want := []bool{true, false, true}
+	if len(got) != len(want) {
+		t.Fatalf("Bools = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Bools = %v, want %v", got, want)
+		}
+	}
+}
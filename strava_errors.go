@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// FieldError is one per-field validation failure from Strava's error
+// envelope, e.g. {"resource":"Activity","field":"name","code":"required"}.
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+}
+
+// StravaAPIError is returned for any non-2xx response from the Strava API.
+type StravaAPIError struct {
+	StatusCode int
+	Message    string
+	Errors     []FieldError
+
+	// RateLimitReset is when the exhausted rate-limit window resets. It's
+	// only populated on errors IsRateLimited() returns true for.
+	RateLimitReset time.Time
+}
+
+func (e *StravaAPIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("strava API error (status %d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("strava API error (status %d): %s: %v", e.StatusCode, e.Message, e.Errors)
+}
+
+// IsAuthError reports whether the access token was missing, expired, or
+// revoked.
+func (e *StravaAPIError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+// IsRateLimited reports whether the request was rejected because a
+// rate-limit window (15-minute or daily) is exhausted.
+func (e *StravaAPIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsValidation reports whether the request failed Strava's per-field
+// validation; Errors holds the offending fields.
+func (e *StravaAPIError) IsValidation() bool {
+	return e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// parseStravaAPIError decodes Strava's {"message":..., "errors":[...]}
+// error envelope. A body that doesn't match the envelope still yields a
+// usable error carrying just the status code.
+func parseStravaAPIError(statusCode int, body []byte) *StravaAPIError {
+	var envelope struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+	return &StravaAPIError{
+		StatusCode: statusCode,
+		Message:    envelope.Message,
+		Errors:     envelope.Errors,
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig controls how StravaClient.do retries transient failures.
+type RetryConfig struct {
+	// MaxRetries is how many additional attempts are made after the first,
+	// for 429s (once a reset time is known) and 5xx responses.
+	MaxRetries int
+
+	// BaseDelay is the backoff unit for 5xx retries: attempt N waits
+	// BaseDelay*2^N plus jitter in [0, BaseDelay).
+	BaseDelay time.Duration
+}
+
+// defaultRetryConfig is used whenever a StravaClient doesn't set Retry.
+var defaultRetryConfig = RetryConfig{MaxRetries: 3, BaseDelay: 500 * time.Millisecond}
+
+// do builds and issues a request via newReq, decoding a *StravaAPIError on
+// any non-2xx response. It transparently retries once on 401 (after
+// refreshing the access token), waits out or reports 429s, and retries 5xx
+// responses with exponential backoff. newReq is called again on every
+// attempt so callers can supply a fresh request body.
+func (c *StravaClient) do(ctx context.Context, newReq func() (*http.Request, error)) ([]byte, error) {
+	retry := c.Retry
+	if retry.MaxRetries == 0 && retry.BaseDelay == 0 {
+		retry = defaultRetryConfig
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+			return body, nil
+		}
+
+		apiErr := parseStravaAPIError(resp.StatusCode, body)
+
+		switch {
+		case apiErr.IsAuthError() && attempt == 0:
+			// Requests already go through oauth.Transport, which calls
+			// AccessToken (and refreshes if the cache believes the token
+			// expired) before every attempt. A 401 despite that means the
+			// cache's notion of validity is wrong (revocation, clock skew, a
+			// scope change), so AccessToken would just hand back the same
+			// stale token; force the refresh instead.
+			if _, refreshErr := c.OAuth.ForceRefresh(ctx); refreshErr != nil {
+				return nil, apiErr
+			}
+
+		case apiErr.IsRateLimited():
+			reset, ok := rateLimitReset(resp.Header)
+			apiErr.RateLimitReset = reset
+			if !ok || attempt >= retry.MaxRetries {
+				return nil, apiErr
+			}
+			if wait := time.Until(reset); wait > 0 {
+				if err := sleepCtx(ctx, wait); err != nil {
+					return nil, err
+				}
+			}
+
+		case resp.StatusCode >= 500 && attempt < retry.MaxRetries:
+			if err := sleepCtx(ctx, retryBackoff(retry, attempt)); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, apiErr
+		}
+	}
+}
+
+// rateLimitReset derives when Strava's current rate-limit window resets
+// from the X-RateLimit-Usage/X-RateLimit-Limit headers. Strava documents
+// both as comma-separated "15min,daily" pairs but doesn't return an
+// explicit reset timestamp, so we compute it ourselves: if the daily bucket
+// is the one that's exhausted, the reset is the next UTC midnight; otherwise
+// it's the start of the next 15-minute window.
+func rateLimitReset(h http.Header) (time.Time, bool) {
+	limitHeader := h.Get("X-RateLimit-Limit")
+	usageHeader := h.Get("X-RateLimit-Usage")
+	if limitHeader == "" && usageHeader == "" {
+		return time.Time{}, false
+	}
+
+	now := time.Now().UTC()
+	nextQuarterHour := now.Truncate(15 * time.Minute).Add(15 * time.Minute)
+
+	shortLimit, dailyLimit, limitOK := parseRateLimitPair(limitHeader)
+	shortUsage, dailyUsage, usageOK := parseRateLimitPair(usageHeader)
+	if !limitOK || !usageOK {
+		return nextQuarterHour, true
+	}
+
+	// The daily bucket takes priority: if it's exhausted, waiting out the
+	// 15-minute window (even when that one's also exhausted) just burns a
+	// retry on a request that's guaranteed to 429 again.
+	if dailyUsage >= dailyLimit {
+		nextMidnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+		return nextMidnight, true
+	}
+	if shortUsage >= shortLimit {
+		return nextQuarterHour, true
+	}
+	// Neither bucket reports as exhausted despite the 429; fall back to the
+	// short window as the safest guess.
+	return nextQuarterHour, true
+}
+
+// parseRateLimitPair splits a Strava rate-limit header value of the form
+// "15min,daily" into its two integers.
+func parseRateLimitPair(v string) (short, daily int, ok bool) {
+	parts := strings.Split(v, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	short, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+	daily, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, false
+	}
+	return short, daily, true
+}
+
+func retryBackoff(retry RetryConfig, attempt int) time.Duration {
+	backoff := retry.BaseDelay << attempt
+	if retry.BaseDelay > 0 {
+		backoff += time.Duration(rand.Int63n(int64(retry.BaseDelay)))
+	}
+	return backoff
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
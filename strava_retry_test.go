@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/johntaormina/backend-runner/oauth"
+)
+
+func TestParseStravaAPIError(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusCode     int
+		body           string
+		wantMessage    string
+		wantFieldCount int
+		wantAuth       bool
+		wantRateLimit  bool
+		wantValidation bool
+	}{
+		{
+			name:        "unauthorized",
+			statusCode:  http.StatusUnauthorized,
+			body:        `{"message":"Authorization Error","errors":[]}`,
+			wantMessage: "Authorization Error",
+			wantAuth:    true,
+		},
+		{
+			name:          "rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			body:          `{"message":"Rate Limit Exceeded"}`,
+			wantMessage:   "Rate Limit Exceeded",
+			wantRateLimit: true,
+		},
+		{
+			name:           "validation with field errors",
+			statusCode:     http.StatusUnprocessableEntity,
+			body:           `{"message":"Bad Request","errors":[{"resource":"Activity","field":"name","code":"required"}]}`,
+			wantMessage:    "Bad Request",
+			wantFieldCount: 1,
+			wantValidation: true,
+		},
+		{
+			name:       "unparseable body still carries status",
+			statusCode: http.StatusInternalServerError,
+			body:       "not json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseStravaAPIError(tt.statusCode, []byte(tt.body))
+			if err.StatusCode != tt.statusCode {
+				t.Errorf("StatusCode = %d, want %d", err.StatusCode, tt.statusCode)
+			}
+			if err.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", err.Message, tt.wantMessage)
+			}
+			if len(err.Errors) != tt.wantFieldCount {
+				t.Errorf("len(Errors) = %d, want %d", len(err.Errors), tt.wantFieldCount)
+			}
+			if err.IsAuthError() != tt.wantAuth {
+				t.Errorf("IsAuthError() = %v, want %v", err.IsAuthError(), tt.wantAuth)
+			}
+			if err.IsRateLimited() != tt.wantRateLimit {
+				t.Errorf("IsRateLimited() = %v, want %v", err.IsRateLimited(), tt.wantRateLimit)
+			}
+			if err.IsValidation() != tt.wantValidation {
+				t.Errorf("IsValidation() = %v, want %v", err.IsValidation(), tt.wantValidation)
+			}
+		})
+	}
+}
+
+// newTestClient builds a StravaClient pointed at server with no OAuth
+// round-tripping, since these tests exercise do's retry logic directly, not
+// authentication.
+func newTestClient(server *httptest.Server, retry RetryConfig) *StravaClient {
+	cache := oauth.NewMemoryCache()
+	oauthClient := oauth.NewClient(oauth.OAuth{SegmentName: "test", AccessTokenKey: "a", RefreshTokenKey: "r"}, cache)
+	_ = oauthClient.Save(oauth.Token{AccessToken: "token123"})
+	return &StravaClient{
+		OAuth: oauthClient,
+		HTTP:  server.Client(),
+		Retry: retry,
+	}
+}
+
+func TestDoRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"temporarily unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server, RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	body, err := client.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("body = %q", body)
+	}
+}
+
+func TestDoGivesUpOn5xxAfterMaxRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"down"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server, RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond})
+
+	_, err := client.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	var apiErr *StravaAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a *StravaAPIError, got %T", err)
+	}
+	if calls != 3 { // initial attempt + 2 retries
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDoReturnsTypedErrorOnRateLimitWithoutWaiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100,1000")
+		w.Header().Set("X-RateLimit-Usage", "100,1000")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"Rate Limit Exceeded"}`))
+	}))
+	defer server.Close()
+
+	// MaxRetries: 0 means the 429 is reported immediately rather than
+	// waited out, so the test doesn't block until the next 15-minute
+	// window.
+	client := newTestClient(server, RetryConfig{MaxRetries: 0, BaseDelay: time.Millisecond})
+
+	_, err := client.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	})
+	var apiErr *StravaAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a *StravaAPIError, got %T (%v)", err, err)
+	}
+	if !apiErr.IsRateLimited() {
+		t.Fatal("expected IsRateLimited() to be true")
+	}
+	if apiErr.RateLimitReset.IsZero() {
+		t.Fatal("expected RateLimitReset to be set")
+	}
+}
+
+func TestDoRefreshesOnceOn401ThenSucceeds(t *testing.T) {
+	var tokenCalls int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"refreshed-token","refresh_token":"refresh123","expires_at":` +
+			strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10) + `}`))
+	}))
+	defer tokenServer.Close()
+
+	var apiCalls int
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		if apiCalls == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Authorization Error"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer apiServer.Close()
+
+	cache := oauth.NewMemoryCache()
+	oauthClient := oauth.NewClient(oauth.OAuth{SegmentName: "test", AccessTokenKey: "a", RefreshTokenKey: "r", TokenURL: tokenServer.URL}, cache)
+	_ = oauthClient.Save(oauth.Token{AccessToken: "stale-token", RefreshToken: "refresh123", ExpiresAt: time.Now().Add(time.Hour)})
+
+	client := &StravaClient{OAuth: oauthClient, HTTP: apiServer.Client(), Retry: RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}}
+
+	body, err := client.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, apiServer.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if apiCalls != 2 {
+		t.Fatalf("apiCalls = %d, want 2", apiCalls)
+	}
+	if tokenCalls != 1 {
+		t.Fatalf("tokenCalls = %d, want 1 (the 401 branch should force a refresh against the token endpoint)", tokenCalls)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("body = %q", body)
+	}
+}
+
+func TestDoReturnsAuthErrorWhenRefreshFails(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Authorization Error"}`))
+	}))
+	defer apiServer.Close()
+
+	cache := oauth.NewMemoryCache()
+	oauthClient := oauth.NewClient(oauth.OAuth{SegmentName: "test", AccessTokenKey: "a", RefreshTokenKey: "r", TokenURL: tokenServer.URL}, cache)
+	_ = oauthClient.Save(oauth.Token{AccessToken: "stale-token", RefreshToken: "refresh123", ExpiresAt: time.Now().Add(time.Hour)})
+
+	client := &StravaClient{OAuth: oauthClient, HTTP: apiServer.Client(), Retry: RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}}
+
+	_, err := client.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), http.MethodGet, apiServer.URL, nil)
+	})
+	var apiErr *StravaAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a *StravaAPIError, got %T (%v)", err, err)
+	}
+	if !apiErr.IsAuthError() {
+		t.Fatal("expected IsAuthError() to be true")
+	}
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Repo persists SyncTasks in SQLite via database/sql. Callers are
+// responsible for importing the driver they want (e.g. mattn/go-sqlite3) and
+// opening the *sql.DB.
+type Repo struct {
+	db *sql.DB
+}
+
+// NewRepo wraps an already-open database handle.
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// CreateTableIfNotExist creates the sync_tasks table used to persist
+// SyncTasks, if it doesn't already exist.
+func (r *Repo) CreateTableIfNotExist(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS sync_tasks (
+	id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+	source_token        TEXT NOT NULL,
+	dest_token          TEXT NOT NULL,
+	last_seen_timestamp INTEGER NOT NULL
+)`)
+	return err
+}
+
+// RetrieveAllSyncTasks loads every persisted SyncTask.
+func (r *Repo) RetrieveAllSyncTasks(ctx context.Context) ([]SyncTask, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, source_token, dest_token, last_seen_timestamp FROM sync_tasks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []SyncTask
+	for rows.Next() {
+		var task SyncTask
+		var lastSeen int64
+		if err := rows.Scan(&task.ID, &task.SourceToken, &task.DestToken, &lastSeen); err != nil {
+			return nil, err
+		}
+		task.LastSeenTimestamp = time.Unix(lastSeen, 0).UTC()
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// UpdateSyncTask persists a task's advanced LastSeenTimestamp.
+func (r *Repo) UpdateSyncTask(ctx context.Context, task SyncTask) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE sync_tasks SET last_seen_timestamp = ? WHERE id = ?`,
+		task.LastSeenTimestamp.Unix(), task.ID)
+	return err
+}
@@ -0,0 +1,19 @@
+// Package sync implements a provider-agnostic worker that keeps two
+// FitnessProvider accounts in sync, inspired by syncmysport-runkeeper.
+package sync
+
+import "time"
+
+// SyncTask describes one source-to-destination sync pairing. Tasks are
+// persisted so the worker can resume where it left off across restarts.
+type SyncTask struct {
+	ID int64
+
+	SourceToken string
+	DestToken   string
+
+	// LastSeenTimestamp is the cutoff used for the next pull from the
+	// source provider. It's advanced, with a small negative delta, each
+	// time new activities are uploaded.
+	LastSeenTimestamp time.Time
+}
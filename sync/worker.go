@@ -0,0 +1,139 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/johntaormina/backend-runner/provider"
+)
+
+// lookbackDelta is subtracted from the latest uploaded activity's start time
+// before it's persisted as LastSeenTimestamp, so a source activity that
+// arrives slightly out of order isn't skipped on the next run.
+const lookbackDelta = -45 * time.Minute
+
+// startTimeTolerance and durationTolerance bound how far apart two
+// activities' start time and duration can be and still be considered the
+// same workout. Providers round-trip these values imprecisely (sub-second
+// truncation, moving vs. elapsed time, timezone handling), so matching on
+// exact equality misses real duplicates and re-uploads them every cycle.
+const (
+	startTimeTolerance = 5 * time.Minute
+	durationTolerance  = 30 * time.Second
+)
+
+// Worker polls every persisted SyncTask, pulling new activities from the
+// source provider and uploading whatever the destination is missing.
+type Worker struct {
+	Repo *Repo
+
+	// NewSource and NewDest build a provider client from a task's
+	// SourceToken / DestToken respectively.
+	NewSource provider.Factory
+	NewDest   provider.Factory
+
+	PollInterval time.Duration
+}
+
+// Run polls on PollInterval until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil {
+			log.Printf("sync: run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce syncs every persisted task a single time.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	tasks, err := w.Repo.RetrieveAllSyncTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve sync tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		if err := w.syncTask(ctx, task); err != nil {
+			log.Printf("sync: task %d failed: %v", task.ID, err)
+		}
+	}
+	return nil
+}
+
+func (w *Worker) syncTask(ctx context.Context, task SyncTask) error {
+	source, err := w.NewSource(task.SourceToken)
+	if err != nil {
+		return fmt.Errorf("build source provider: %w", err)
+	}
+	dest, err := w.NewDest(task.DestToken)
+	if err != nil {
+		return fmt.Errorf("build destination provider: %w", err)
+	}
+
+	sourceActivities, err := source.ListActivities(ctx, task.LastSeenTimestamp)
+	if err != nil {
+		return fmt.Errorf("list source activities: %w", err)
+	}
+	if len(sourceActivities) == 0 {
+		return nil
+	}
+
+	destActivities, err := dest.ListActivities(ctx, task.LastSeenTimestamp)
+	if err != nil {
+		return fmt.Errorf("list destination activities: %w", err)
+	}
+
+	var latestStart time.Time
+	for _, a := range sourceActivities {
+		if a.StartTime.After(latestStart) {
+			latestStart = a.StartTime
+		}
+		if activityExists(a, destActivities) {
+			continue
+		}
+		if err := dest.UploadActivity(ctx, a); err != nil {
+			return fmt.Errorf("upload activity %s: %w", a.ID, err)
+		}
+	}
+
+	if latestStart.IsZero() {
+		return nil
+	}
+	task.LastSeenTimestamp = latestStart.Add(lookbackDelta)
+	return w.Repo.UpdateSyncTask(ctx, task)
+}
+
+// activityExists reports whether existing contains an activity matching a's
+// start time and duration within tolerance, since the same workout can have
+// different IDs (and slightly different recorded start time/duration) across
+// providers.
+func activityExists(a provider.Activity, existing []provider.Activity) bool {
+	for _, e := range existing {
+		if activitiesMatch(a, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func activitiesMatch(a, b provider.Activity) bool {
+	return absDuration(a.StartTime.Sub(b.StartTime)) <= startTimeTolerance &&
+		absDuration(a.Duration-b.Duration) <= durationTolerance
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
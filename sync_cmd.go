@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/johntaormina/backend-runner/oauth"
+	"github.com/johntaormina/backend-runner/provider"
+	"github.com/johntaormina/backend-runner/sync"
+)
+
+const syncDBPath = "sync.db"
+
+// runSyncCommand backs the `runner sync` subcommand: it opens the sync task
+// database and runs the Strava<->Runkeeper sync worker until interrupted.
+func runSyncCommand(ctx context.Context) error {
+	db, err := sql.Open("sqlite3", syncDBPath)
+	if err != nil {
+		return fmt.Errorf("open sync db: %w", err)
+	}
+	defer db.Close()
+
+	repo := sync.NewRepo(db)
+	if err := repo.CreateTableIfNotExist(ctx); err != nil {
+		return fmt.Errorf("create sync tasks table: %w", err)
+	}
+
+	worker := &sync.Worker{
+		Repo:         repo,
+		NewSource:    stravaProviderFactory(),
+		NewDest:      runkeeperProviderFactory(),
+		PollInterval: 15 * time.Minute,
+	}
+
+	return worker.Run(ctx)
+}
+
+// stravaProviderFactory builds a provider.Factory that authenticates each
+// call with the task's stored Strava access token.
+func stravaProviderFactory() provider.Factory {
+	config := StravaConfig{
+		ClientID:     os.Getenv("STRAVA_CLIENT_ID"),
+		ClientSecret: os.Getenv("STRAVA_CLIENT_SECRET"),
+		RedirectURI:  os.Getenv("STRAVA_REDIRECT_URI"),
+	}
+
+	return func(accessToken string) (provider.FitnessProvider, error) {
+		cache := oauth.NewMemoryCache()
+		oauthClient := newStravaOAuth(config, cache)
+		if err := oauthClient.Save(oauth.Token{AccessToken: accessToken}); err != nil {
+			return nil, err
+		}
+
+		client := &StravaClient{
+			Config: config,
+			OAuth:  oauthClient,
+			HTTP:   oauthClient.AuthenticatedHTTPClient(),
+		}
+		return NewStravaFitnessProvider(client), nil
+	}
+}
+
+// runkeeperProviderFactory builds a provider.Factory that authenticates each
+// call with the task's stored Runkeeper access token.
+func runkeeperProviderFactory() provider.Factory {
+	config := provider.RunkeeperConfig{
+		ClientID:     os.Getenv("RUNKEEPER_CLIENT_ID"),
+		ClientSecret: os.Getenv("RUNKEEPER_CLIENT_SECRET"),
+		RedirectURI:  os.Getenv("RUNKEEPER_REDIRECT_URI"),
+	}
+
+	return func(accessToken string) (provider.FitnessProvider, error) {
+		return provider.NewRunkeeperProvider(config, accessToken), nil
+	}
+}